@@ -0,0 +1,122 @@
+// Package data holds the intermediate representation registry.Analyse
+// produces from a CodeGeneratorRequest. The generator package renders these
+// structures into TypeScript; nothing in this package talks to descriptor
+// protos or the filesystem directly.
+package data
+
+import "github.com/juanjiTech/protoc-gen-grpc-gateway-ts/options"
+
+// File is everything registry.Analyse extracted from a single proto file.
+type File struct {
+	// Name is the proto file path, e.g. "foo/bar.proto"
+	Name string
+	// TSFileName is Name with its ".proto" extension swapped for ".pb.ts"
+	TSFileName string
+	Services   Services
+	Messages   []*Message
+	// EnableStylingCheck is copied down from the generator before rendering
+	EnableStylingCheck bool
+	// FetchModuleImportPath is the TS import specifier this file uses to reach
+	// the shared fetch module, relative to this file's own directory rather
+	// than assuming it sits next to every generated file.
+	FetchModuleImportPath string
+}
+
+// IsEmpty reports whether the file declares no messages or services, in
+// which case the generator emits a stub "export default {}" instead of
+// running it through the template.
+func (f *File) IsEmpty() bool {
+	return len(f.Messages) == 0 && len(f.Services) == 0
+}
+
+// Services is a slice of Service with generator-facing helpers attached.
+type Services []*Service
+
+// NeedsFetchModule reports whether any of these services has at least one
+// RPC, meaning the shared fetch module needs to be generated alongside them.
+func (s Services) NeedsFetchModule() bool {
+	for _, svc := range s {
+		if len(svc.Methods) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsDuplexTransport reports whether any of these services has at least
+// one bidirectional-streaming method, meaning the fetch module's
+// WebSocketDuplexTransport needs to be imported alongside Transport.
+func (s Services) NeedsDuplexTransport() bool {
+	for _, svc := range s {
+		if svc.NeedsDuplexTransport() {
+			return true
+		}
+	}
+	return false
+}
+
+// Service is a single `service` declaration.
+type Service struct {
+	Name    string
+	Methods []*Method
+}
+
+// NeedsDuplexTransport reports whether any method on this service is
+// bidirectional streaming, meaning the generated class needs a
+// WebSocketDuplexTransport alongside its Transport.
+func (s *Service) NeedsDuplexTransport() bool {
+	for _, m := range s.Methods {
+		if m.ClientStreaming && m.ServerStreaming {
+			return true
+		}
+	}
+	return false
+}
+
+// Method is a single RPC, with its google.api.http binding resolved.
+type Method struct {
+	Name string
+	// HTTPMethod is the HTTP verb the google.api.http binding resolved to, e.g. "GET"
+	HTTPMethod string
+	// Pattern is the URL path template from the google.api.http binding, e.g. "/v1/widgets/{id}"
+	Pattern string
+	// Body is the google.api.http `body` selector ("*", a field name, or "" for none)
+	Body            string
+	RequestType     *Message
+	ResponseType    *Message
+	ServerStreaming bool
+	ClientStreaming bool
+	// ScenarioExecutor is set when the RPC carries a scenario_executor method option
+	ScenarioExecutor *options.ScenarioExecutor
+}
+
+// Message is a single proto `message`.
+type Message struct {
+	Name   string
+	Fields []*Field
+}
+
+// Field is a single message field.
+type Field struct {
+	Name     string
+	JSONName string
+	// Type is the field's rendered TS type, ignoring presence (e.g. "string", "Widget")
+	Type     string
+	Presence FieldPresence
+}
+
+// FieldPresence captures how a field's absence round-trips between proto2,
+// proto3 and Editions, mirroring google.protobuf.FeatureSet_FieldPresence.
+type FieldPresence int
+
+const (
+	// FieldPresenceImplicit is a plain proto3 field, or an Editions field with
+	// `field_presence = IMPLICIT`: it has no hazzer and decodes its zero value.
+	FieldPresenceImplicit FieldPresence = iota
+	// FieldPresenceExplicit is a proto2/proto3 `optional` field, or an Editions
+	// field with `field_presence = EXPLICIT`: it has a real hazzer.
+	FieldPresenceExplicit
+	// FieldPresenceLegacyRequired is a proto2 `required` field, or an Editions
+	// field with `field_presence = LEGACY_REQUIRED`: it is always set.
+	FieldPresenceLegacyRequired
+)
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/data"
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// EditionsDefaultOption sets the default Protobuf Editions feature set
+// (e.g. "2023") applied to files that don't set `features.field_presence`
+// explicitly, mirroring how upstream Go generators resolve edition defaults.
+// registry.Analyse consults this as the fallback presence for an Editions
+// file with no explicit `features.field_presence` override.
+const EditionsDefaultOption = registry.EditionsDefaultOption
+
+func init() {
+	FuncMap["tsFieldType"] = tsFieldType
+}
+
+// tsFieldType renders the TS type for a field according to its presence
+// semantics, as attached by registry.Analyse for proto2/proto3/Editions
+// inputs:
+//   - EXPLICIT presence (proto2 `optional`, proto3 `optional`, or an Editions
+//     file with `field_presence = EXPLICIT`) has a real hazzer, so the field
+//     may be entirely absent: `T | undefined`
+//   - LEGACY_REQUIRED (proto2 `required`) is always set: `T`
+//   - IMPLICIT presence (plain proto3 fields, or Editions
+//     `field_presence = IMPLICIT`) round-trips its zero value as `null` the
+//     same way the existing proto3 codegen already does: `T | null`
+func tsFieldType(field *data.Field) string {
+	switch field.Presence {
+	case data.FieldPresenceExplicit:
+		return field.Type + " | undefined"
+	case data.FieldPresenceLegacyRequired:
+		return field.Type
+	default:
+		return field.Type + " | null"
+	}
+}
@@ -22,6 +22,34 @@ type TypeScriptGRPCGatewayGenerator struct {
 	// This option will only turn on in integration test to ensure the readability in
 	// the generated code.
 	EnableStylingCheck bool
+	// EmitOpenAPI writes an OpenAPI v3 sidecar document alongside the generated TS files
+	EmitOpenAPI bool
+	// OpenAPIMergeFilename, when set, merges every OpenAPI document into a single file at this path
+	OpenAPIMergeFilename string
+	// OpenAPIHost populates the `servers[0].url` entry of the generated OpenAPI document
+	OpenAPIHost string
+	// Transport selects the Transport implementation backing the generated fetch module
+	// (fetch|axios|xhr|node-fetch|custom)
+	Transport string
+	// CustomTransportImport is the import path for a user supplied Transport implementation,
+	// used when Transport is "custom"
+	CustomTransportImport string
+	// EmitK6Scenarios writes a `*.scenarios.ts` k6 load-test stub for every RPC
+	// annotated with the scenario_executor method option
+	EmitK6Scenarios bool
+	// EnableIncrementalGeneration turns on skipping files whose content hash matches
+	// the incremental generation manifest from a previous run
+	EnableIncrementalGeneration bool
+	// ManifestPath overrides where the incremental generation manifest is read from and written to
+	ManifestPath string
+	// RawParams is the plugin params map New was called with, folded into the manifest hash
+	// so changing a param invalidates cached entries
+	RawParams map[string]string
+	// EditionsDefault is the default Protobuf Editions feature set applied to files
+	// that don't set `features.field_presence` explicitly
+	EditionsDefault string
+	// Streaming picks how server-streaming responses are decoded (sse|ndjson|auto)
+	Streaming string
 }
 
 const (
@@ -44,8 +72,19 @@ func New(paramsMap map[string]string) (*TypeScriptGRPCGatewayGenerator, error) {
 	}
 
 	return &TypeScriptGRPCGatewayGenerator{
-		Registry:           registry,
-		EnableStylingCheck: enableStylingCheck,
+		Registry:                    registry,
+		EnableStylingCheck:          enableStylingCheck,
+		EmitOpenAPI:                 paramsMap[EmitOpenAPIOption] == "true",
+		OpenAPIMergeFilename:        paramsMap[OpenAPIMergeFilenameOption],
+		OpenAPIHost:                 paramsMap[OpenAPIHostOption],
+		Transport:                   paramsMap[TransportOption],
+		CustomTransportImport:       paramsMap[CustomTransportImportOption],
+		EmitK6Scenarios:             paramsMap[EmitK6ScenariosOption] == "true",
+		EnableIncrementalGeneration: paramsMap[EnableIncrementalGenerationOption] == "true",
+		ManifestPath:                paramsMap[ManifestPathOption],
+		RawParams:                   paramsMap,
+		EditionsDefault:             paramsMap[EditionsDefaultOption],
+		Streaming:                   paramsMap[StreamingOption],
 	}, nil
 }
 
@@ -59,6 +98,7 @@ func (t *TypeScriptGRPCGatewayGenerator) Generate(req *plugin.CodeGeneratorReque
 	}
 	tmpl := GetTemplate(t.Registry)
 	log.Debugf("files to generate %v", req.GetFileToGenerate())
+	log.Debugf("editions default %q", t.EditionsDefault)
 
 	needToGenerateFetchModule := false
 	// feed fileData into rendering process
@@ -80,7 +120,10 @@ func (t *TypeScriptGRPCGatewayGenerator) Generate(req *plugin.CodeGeneratorReque
 
 	if needToGenerateFetchModule {
 		// generate fetch module
-		fetchTmpl := GetFetchModuleTemplate()
+		fetchTmpl, err := GetFetchModuleTemplate(t.Transport, t.CustomTransportImport, t.Streaming)
+		if err != nil {
+			return nil, errors.Wrap(err, "error resolving fetch module transport template")
+		}
 		log.Debugf("generate fetch template")
 		generatedFetch, err := t.generateFetchModule(fetchTmpl)
 		if err != nil {
@@ -91,6 +134,29 @@ func (t *TypeScriptGRPCGatewayGenerator) Generate(req *plugin.CodeGeneratorReque
 			resp.File = append(resp.File, generatedFetch)
 		}
 	}
+
+	if t.EmitOpenAPI {
+		openAPIFiles, err := t.generateOpenAPIFiles(filesData)
+		if err != nil {
+			return nil, errors.Wrap(err, "error generating openapi sidecar document")
+		}
+		resp.File = append(resp.File, openAPIFiles...)
+	}
+
+	if t.EmitK6Scenarios {
+		k6Files, err := t.generateK6ScenarioFiles(filesData)
+		if err != nil {
+			return nil, errors.Wrap(err, "error generating k6 scenario stubs")
+		}
+		resp.File = append(resp.File, k6Files...)
+	}
+
+	if t.EnableIncrementalGeneration {
+		resp.File, err = t.filterUnchanged(resp.File)
+		if err != nil {
+			return nil, errors.Wrap(err, "error applying incremental generation manifest")
+		}
+	}
 	return resp, nil
 }
 
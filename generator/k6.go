@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"text/template"
+
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/data"
+	"github.com/pkg/errors"
+)
+
+// EmitK6ScenariosOption turns on generation of a `*.scenarios.ts` k6 load-test
+// stub for every RPC annotated with the `scenario_executor` method option
+const EmitK6ScenariosOption = "emit_k6_scenarios"
+
+func init() {
+	FuncMap["tsModuleBase"] = tsModuleBase
+	FuncMap["serviceNames"] = serviceNames
+}
+
+// tsModuleBase returns the bare (no directory, no .ts extension) TS module
+// specifier for a generated file, for importing from the scenario file
+// generated alongside it in the same directory.
+func tsModuleBase(tsFileName string) string {
+	return "./" + path.Base(strings.TrimSuffix(tsFileName, ".ts"))
+}
+
+// serviceNames joins a file's service names for a single combined import.
+func serviceNames(services data.Services) string {
+	names := make([]string, len(services))
+	for i, svc := range services {
+		names[i] = svc.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// k6ScenarioTemplateSrc renders one k6 scenario function per annotated RPC. Seed
+// payloads come from a per-method request factory the caller can override, and
+// requests go through the same Transport abstraction the fetch module exposes
+// rather than hardcoding a URL. The service(s) are imported from the owning
+// file's own generated module, not from a name derived off the service itself,
+// since a proto file renders into a file named after itself, not its service.
+const k6ScenarioTemplateSrc = `import { Transport } from "{{.FetchModuleImportPath}}"
+{{if .Services}}import { {{serviceNames .Services}} } from "{{tsModuleBase .TSFileName}}"
+{{end}}
+
+export const options = {
+  scenarios: {
+{{- range $svc := .Services}}{{range $svc.Methods}}{{if .ScenarioExecutor}}
+    {{.Name}}: {
+{{- if .ScenarioExecutor.ConstantArrivalRate}}
+      executor: "constant-arrival-rate",
+      duration: "{{.ScenarioExecutor.ConstantArrivalRate.Duration}}",
+      rate: {{.ScenarioExecutor.ConstantArrivalRate.Rate}},
+      preAllocatedVUs: {{.ScenarioExecutor.ConstantArrivalRate.PreAllocatedVUs}},
+{{- else if .ScenarioExecutor.RampingVUs}}
+      executor: "ramping-vus",
+      startTime: "{{.ScenarioExecutor.RampingVUs.Duration}}",
+      preAllocatedVUs: {{.ScenarioExecutor.RampingVUs.PreAllocatedVUs}},
+{{- end}}
+      exec: "{{.Name}}",
+    },
+{{end}}{{end}}{{end -}}
+  },
+}
+
+{{range $svc := .Services}}{{range $svc.Methods}}{{if .ScenarioExecutor}}
+// {{.Name}}RequestFactory seeds the request sent by the {{.Name}} scenario.
+// Override this export to feed the scenario different data per run.
+export let {{.Name}}RequestFactory = (): {{.RequestType.Name}} => ({} as {{.RequestType.Name}})
+
+export function {{.Name}}(transport: Transport) {
+  return new {{$svc.Name}}(transport).{{.Name}}({{.Name}}RequestFactory())
+}
+{{end}}{{end}}{{end -}}
+`
+
+// generateK6ScenarioFiles renders a `<file>.scenarios.ts` k6 stub for every
+// fileData that has at least one RPC annotated with scenario_executor.
+func (t *TypeScriptGRPCGatewayGenerator) generateK6ScenarioFiles(filesData []*data.File) ([]*plugin.CodeGeneratorResponse_File, error) {
+	tmpl, err := template.New("k6.scenarios.ts.tmpl").Funcs(FuncMap).Parse(k6ScenarioTemplateSrc)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing k6 scenario template")
+	}
+
+	var files []*plugin.CodeGeneratorResponse_File
+	for _, fileData := range filesData {
+		if !hasScenarios(fileData) {
+			continue
+		}
+
+		w := new(bytes.Buffer)
+		if err := tmpl.Execute(w, fileData); err != nil {
+			return nil, errors.Wrapf(err, "error generating k6 scenarios for %s", fileData.Name)
+		}
+
+		name := strings.TrimSuffix(fileData.TSFileName, ".ts") + ".scenarios.ts"
+		content := strings.TrimSpace(w.String())
+		files = append(files, &plugin.CodeGeneratorResponse_File{
+			Name:    &name,
+			Content: &content,
+		})
+	}
+
+	return files, nil
+}
+
+func hasScenarios(fileData *data.File) bool {
+	for _, svc := range fileData.Services {
+		for _, method := range svc.Methods {
+			if method.ScenarioExecutor != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
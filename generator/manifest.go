@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	log "github.com/sirupsen/logrus" // nolint: depguard
+
+	"github.com/pkg/errors"
+)
+
+// templateVersion is bumped whenever a template's output changes in a way
+// that should invalidate the incremental generation manifest, even if the
+// proto input and plugin params didn't change.
+const templateVersion = "1"
+
+// EnableIncrementalGenerationOption turns on skipping files whose content hash
+// matches the manifest from a previous run. Off by default: protoc plugins are
+// expected to be deterministic pure functions of their input, and skipping
+// files is only safe when the caller actually persists the manifest and the
+// previously generated files between runs, which buf/protoc invocations don't
+// guarantee on their own.
+const EnableIncrementalGenerationOption = "enable_incremental_generation"
+
+// ManifestPathOption overrides where the incremental generation manifest is
+// read from and written to. Defaults to a file next to the fetch module.
+const ManifestPathOption = "manifest_path"
+
+const defaultManifestFilename = ".protoc-gen-grpc-gateway-ts.manifest.json"
+
+// manifest records a content hash per generated TS file name so unchanged
+// files can be skipped on the next run.
+type manifest struct {
+	Entries map[string]string `json:"entries"`
+}
+
+func (t *TypeScriptGRPCGatewayGenerator) manifestPath() string {
+	if t.ManifestPath != "" {
+		return t.ManifestPath
+	}
+	return filepath.ToSlash(filepath.Join(t.Registry.FetchModuleDirectory, defaultManifestFilename))
+}
+
+func loadManifest(path string) (*manifest, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &manifest{Entries: map[string]string{}}, nil
+		}
+		return nil, err
+	}
+
+	m := &manifest{Entries: map[string]string{}}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, errors.Wrapf(err, "error parsing manifest at %s", path)
+	}
+	if m.Entries == nil {
+		m.Entries = map[string]string{}
+	}
+	return m, nil
+}
+
+func (m *manifest) save(path string) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshalling manifest")
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// paramsFingerprint renders the plugin params into a deterministic string so
+// it can be folded into the manifest hash alongside the file content.
+func paramsFingerprint(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(params[k])
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+func contentHash(content, params, version string) string {
+	sum := sha256.Sum256([]byte(version + "\x00" + params + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+// filterUnchanged drops response files whose content hash matches what the
+// manifest already recorded for that file name, turning regeneration into an
+// O(changed) operation for monorepos with hundreds of protos.
+func (t *TypeScriptGRPCGatewayGenerator) filterUnchanged(files []*plugin.CodeGeneratorResponse_File) ([]*plugin.CodeGeneratorResponse_File, error) {
+	path := t.manifestPath()
+	m, err := loadManifest(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading incremental generation manifest")
+	}
+
+	fingerprint := paramsFingerprint(t.RawParams)
+	changed := make([]*plugin.CodeGeneratorResponse_File, 0, len(files))
+	dirty := false
+
+	for _, f := range files {
+		hash := contentHash(f.GetContent(), fingerprint, templateVersion)
+		// A hash match alone isn't enough: the manifest can outlive the files it
+		// describes (a clean checkout, a deleted output directory), so confirm
+		// the file is still on disk before trusting the cached hash to skip it.
+		if _, err := os.Stat(f.GetName()); err == nil && m.Entries[f.GetName()] == hash {
+			log.Debugf("manifest: %s unchanged, skipping", f.GetName())
+			continue
+		}
+		m.Entries[f.GetName()] = hash
+		dirty = true
+		changed = append(changed, f)
+	}
+
+	if dirty {
+		if err := m.save(path); err != nil {
+			return nil, errors.Wrapf(err, "error saving manifest to %s", path)
+		}
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterUnchanged(t *testing.T) {
+	dir := t.TempDir()
+
+	unchangedName := filepath.Join(dir, "widget.pb.ts")
+	require.NoError(t, os.WriteFile(unchangedName, []byte("content-a"), 0o644))
+
+	changedName := filepath.Join(dir, "gadget.pb.ts")
+	require.NoError(t, os.WriteFile(changedName, []byte("stale"), 0o644))
+
+	deletedName := filepath.Join(dir, "gizmo.pb.ts")
+	// deletedName is recorded in the manifest below but never written to disk.
+
+	gen := &TypeScriptGRPCGatewayGenerator{ManifestPath: filepath.Join(dir, "manifest.json")}
+
+	fingerprint := paramsFingerprint(gen.RawParams)
+	m := &manifest{Entries: map[string]string{
+		unchangedName: contentHash("content-a", fingerprint, templateVersion),
+		changedName:   contentHash("stale", fingerprint, templateVersion),
+		deletedName:   contentHash("gone", fingerprint, templateVersion),
+	}}
+	require.NoError(t, m.save(gen.manifestPath()))
+
+	files := []*plugin.CodeGeneratorResponse_File{
+		{Name: strPtr(unchangedName), Content: strPtr("content-a")},
+		{Name: strPtr(changedName), Content: strPtr("fresh")},
+		{Name: strPtr(deletedName), Content: strPtr("gone")},
+	}
+
+	got, err := gen.filterUnchanged(files)
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range got {
+		names = append(names, f.GetName())
+	}
+	// unchangedName is skipped: its hash matches and the file is still on disk.
+	// changedName is kept: its content no longer matches the manifest.
+	// deletedName is kept even though its hash matches, because it's gone from disk.
+	require.ElementsMatch(t, []string{changedName, deletedName}, names)
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,303 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/data"
+	"github.com/pkg/errors"
+)
+
+// oasDocument is a minimal representation of an OpenAPI v3 document, just
+// enough to describe the services/methods/messages a TS client is generated
+// from.
+type oasDocument struct {
+	OpenAPI    string                  `json:"openapi"`
+	Info       oasInfo                 `json:"info"`
+	Servers    []oasServer             `json:"servers,omitempty"`
+	Paths      map[string]*oasPathItem `json:"paths"`
+	Components oasComponents           `json:"components"`
+}
+
+type oasInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type oasServer struct {
+	URL string `json:"url"`
+}
+
+type oasComponents struct {
+	Schemas map[string]*oasSchema `json:"schemas"`
+}
+
+type oasPathItem struct {
+	Get    *oasOperation `json:"get,omitempty"`
+	Post   *oasOperation `json:"post,omitempty"`
+	Put    *oasOperation `json:"put,omitempty"`
+	Patch  *oasOperation `json:"patch,omitempty"`
+	Delete *oasOperation `json:"delete,omitempty"`
+}
+
+type oasOperation struct {
+	OperationID string                  `json:"operationId"`
+	Tags        []string                `json:"tags,omitempty"`
+	Parameters  []*oasParameter         `json:"parameters,omitempty"`
+	RequestBody *oasRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*oasResponse `json:"responses"`
+}
+
+type oasParameter struct {
+	Name     string     `json:"name"`
+	In       string     `json:"in"`
+	Required bool       `json:"required"`
+	Schema   *oasSchema `json:"schema"`
+}
+
+type oasRequestBody struct {
+	Content map[string]*oasMediaType `json:"content"`
+}
+
+type oasResponse struct {
+	Description string                   `json:"description"`
+	Content     map[string]*oasMediaType `json:"content"`
+}
+
+type oasMediaType struct {
+	Schema *oasSchema `json:"schema"`
+}
+
+type oasSchema struct {
+	Ref        string                `json:"$ref,omitempty"`
+	Type       string                `json:"type,omitempty"`
+	Format     string                `json:"format,omitempty"`
+	Items      *oasSchema            `json:"items,omitempty"`
+	Properties map[string]*oasSchema `json:"properties,omitempty"`
+}
+
+const (
+	// EmitOpenAPIOption turns on the OpenAPI v3 sidecar document output
+	EmitOpenAPIOption = "emit_openapi"
+	// OpenAPIMergeFilenameOption, when set, merges every file's OpenAPI document
+	// into a single file at this path instead of emitting one sidecar per proto file
+	OpenAPIMergeFilenameOption = "openapi_merge_filename"
+	// OpenAPIHostOption sets the `servers[0].url` entry of the generated document
+	OpenAPIHostOption = "openapi_host"
+)
+
+func schemaRef(name string) *oasSchema {
+	return &oasSchema{Ref: "#/components/schemas/" + name}
+}
+
+func messageSchema(msg *data.Message) *oasSchema {
+	properties := map[string]*oasSchema{}
+	for _, field := range msg.Fields {
+		properties[field.JSONName] = fieldSchema(field)
+	}
+	return &oasSchema{Type: "object", Properties: properties}
+}
+
+// fieldSchema maps a field's rendered TS type (data.Field.Type, which ignores
+// presence) to an OAS schema: a scalar type, a byte-string for bytes fields,
+// an array wrapping the element schema for repeated fields, or a $ref to
+// "#/components/schemas/<TS type>" for any other type. The $ref uses the TS
+// type as is, which is package-qualified for messages declared in a package,
+// while component schemas exist only for messages and are keyed by their
+// short name; enums and package-qualified names get no matching component.
+func fieldSchema(field *data.Field) *oasSchema {
+	if base, ok := strings.CutSuffix(field.Type, "[]"); ok {
+		return &oasSchema{Type: "array", Items: scalarOrRefSchema(base)}
+	}
+	return scalarOrRefSchema(field.Type)
+}
+
+func scalarOrRefSchema(tsType string) *oasSchema {
+	switch tsType {
+	case "string":
+		return &oasSchema{Type: "string"}
+	case "number":
+		return &oasSchema{Type: "number"}
+	case "boolean":
+		return &oasSchema{Type: "boolean"}
+	case "Uint8Array":
+		return &oasSchema{Type: "string", Format: "byte"}
+	default:
+		return schemaRef(tsType)
+	}
+}
+
+// pathParameters extracts the `{name}` path parameters grpc-gateway's
+// google.api.http pattern syntax declares, in the order they appear.
+func pathParameters(pattern string) []*oasParameter {
+	var params []*oasParameter
+	for {
+		start := strings.IndexByte(pattern, '{')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(pattern[start:], '}')
+		if end == -1 {
+			break
+		}
+		name := pattern[start+1 : start+end]
+		if eq := strings.IndexByte(name, '='); eq != -1 {
+			name = name[:eq]
+		}
+		params = append(params, &oasParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &oasSchema{Type: "string"},
+		})
+		pattern = pattern[start+end+1:]
+	}
+	return params
+}
+
+// buildOpenAPIDocument renders the services, methods and messages carried by
+// fileData into an OpenAPI v3 document. It reuses the same HTTP method/path
+// information the TS client rendering already walks off registry.Analyse.
+func buildOpenAPIDocument(fileData *data.File, host string) *oasDocument {
+	doc := &oasDocument{
+		OpenAPI: "3.0.3",
+		Info:    oasInfo{Title: fileData.Name, Version: "1.0"},
+		Paths:   map[string]*oasPathItem{},
+		Components: oasComponents{
+			Schemas: map[string]*oasSchema{},
+		},
+	}
+	if host != "" {
+		doc.Servers = []oasServer{{URL: host}}
+	}
+
+	for _, msg := range fileData.Messages {
+		doc.Components.Schemas[msg.Name] = messageSchema(msg)
+	}
+
+	for _, svc := range fileData.Services {
+		for _, method := range svc.Methods {
+			doc.Components.Schemas[method.RequestType.Name] = messageSchema(method.RequestType)
+			doc.Components.Schemas[method.ResponseType.Name] = messageSchema(method.ResponseType)
+
+			item, ok := doc.Paths[method.Pattern]
+			if !ok {
+				item = &oasPathItem{}
+				doc.Paths[method.Pattern] = item
+			}
+
+			responseContentType := "application/json"
+			if method.ServerStreaming {
+				responseContentType = "text/event-stream"
+			}
+
+			op := &oasOperation{
+				OperationID: svc.Name + "_" + method.Name,
+				Tags:        []string{svc.Name},
+				Parameters:  pathParameters(method.Pattern),
+				Responses: map[string]*oasResponse{
+					"200": {
+						Description: method.Name + " response",
+						Content: map[string]*oasMediaType{
+							responseContentType: {Schema: schemaRef(method.ResponseType.Name)},
+						},
+					},
+				},
+			}
+			if method.Body != "" {
+				op.RequestBody = &oasRequestBody{
+					Content: map[string]*oasMediaType{
+						"application/json": {Schema: schemaRef(method.RequestType.Name)},
+					},
+				}
+			}
+
+			switch strings.ToUpper(method.HTTPMethod) {
+			case "GET":
+				item.Get = op
+			case "PUT":
+				item.Put = op
+			case "PATCH":
+				item.Patch = op
+			case "DELETE":
+				item.Delete = op
+			default:
+				item.Post = op
+			}
+		}
+	}
+
+	return doc
+}
+
+func mergeOpenAPIDocuments(docs []*oasDocument, host string) *oasDocument {
+	merged := &oasDocument{
+		OpenAPI: "3.0.3",
+		Info:    oasInfo{Title: "merged", Version: "1.0"},
+		Paths:   map[string]*oasPathItem{},
+		Components: oasComponents{
+			Schemas: map[string]*oasSchema{},
+		},
+	}
+	if host != "" {
+		merged.Servers = []oasServer{{URL: host}}
+	}
+	for _, doc := range docs {
+		for path, item := range doc.Paths {
+			merged.Paths[path] = item
+		}
+		for name, schema := range doc.Components.Schemas {
+			merged.Components.Schemas[name] = schema
+		}
+	}
+	return merged
+}
+
+// generateOpenAPIFiles renders the companion OpenAPI v3 sidecar document(s)
+// for filesData. When t.OpenAPIMergeFilename is set, every file's document is
+// merged into a single output; otherwise each proto file gets its own
+// `<file>.openapi.json` next to the generated TS client.
+func (t *TypeScriptGRPCGatewayGenerator) generateOpenAPIFiles(filesData []*data.File) ([]*plugin.CodeGeneratorResponse_File, error) {
+	var files []*plugin.CodeGeneratorResponse_File
+	var docs []*oasDocument
+
+	for _, fileData := range filesData {
+		if len(fileData.Services) == 0 {
+			continue
+		}
+		doc := buildOpenAPIDocument(fileData, t.OpenAPIHost)
+		if t.OpenAPIMergeFilename != "" {
+			docs = append(docs, doc)
+			continue
+		}
+
+		content, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, errors.Wrapf(err, "error marshalling openapi document for %s", fileData.Name)
+		}
+		name := strings.TrimSuffix(fileData.TSFileName, ".ts") + ".openapi.json"
+		contentStr := string(content)
+		files = append(files, &plugin.CodeGeneratorResponse_File{
+			Name:    &name,
+			Content: &contentStr,
+		})
+	}
+
+	if t.OpenAPIMergeFilename != "" && len(docs) > 0 {
+		merged := mergeOpenAPIDocuments(docs, t.OpenAPIHost)
+		content, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "error marshalling merged openapi document")
+		}
+		name := t.OpenAPIMergeFilename
+		contentStr := string(content)
+		files = append(files, &plugin.CodeGeneratorResponse_File{
+			Name:    &name,
+			Content: &contentStr,
+		})
+	}
+
+	return files, nil
+}
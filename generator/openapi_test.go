@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *data.Field
+		want *oasSchema
+	}{
+		{"string", &data.Field{Type: "string"}, &oasSchema{Type: "string"}},
+		{"number", &data.Field{Type: "number"}, &oasSchema{Type: "number"}},
+		{"boolean", &data.Field{Type: "boolean"}, &oasSchema{Type: "boolean"}},
+		{"bytes", &data.Field{Type: "Uint8Array"}, &oasSchema{Type: "string", Format: "byte"}},
+		{"message ref", &data.Field{Type: "Widget"}, &oasSchema{Ref: "#/components/schemas/Widget"}},
+		{"repeated scalar", &data.Field{Type: "string[]"}, &oasSchema{Type: "array", Items: &oasSchema{Type: "string"}}},
+		{"repeated message", &data.Field{Type: "Widget[]"}, &oasSchema{Type: "array", Items: &oasSchema{Ref: "#/components/schemas/Widget"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldSchema(tt.in))
+		})
+	}
+}
+
+func TestMessageSchema(t *testing.T) {
+	msg := &data.Message{
+		Name: "Widget",
+		Fields: []*data.Field{
+			{JSONName: "id", Type: "string"},
+			{JSONName: "tags", Type: "string[]"},
+		},
+	}
+
+	schema := messageSchema(msg)
+	require.Equal(t, "object", schema.Type)
+	assert.Equal(t, &oasSchema{Type: "string"}, schema.Properties["id"])
+	assert.Equal(t, &oasSchema{Type: "array", Items: &oasSchema{Type: "string"}}, schema.Properties["tags"])
+}
+
+func TestPathParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{"no params", "/v1/widgets", nil},
+		{"one param", "/v1/widgets/{id}", []string{"id"}},
+		{"multiple params", "/v1/widgets/{widgetId}/parts/{partId}", []string{"widgetId", "partId"}},
+		{"param with path capture", "/v1/{name=widgets/*}", []string{"name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := pathParameters(tt.pattern)
+			var names []string
+			for _, p := range params {
+				assert.Equal(t, "path", p.In)
+				assert.True(t, p.Required)
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"text/template"
+
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/registry"
+)
+
+// FuncMap is shared by every template this package parses, so helpers like
+// tsFieldType (registered from editions.go's init) are available everywhere
+// without each template construction site having to know about them.
+var FuncMap = template.FuncMap{}
+
+// fileTemplateSrc renders one `interface` per message and one `class` per
+// service for a single proto file. The Transport import is resolved relative
+// to this file's own directory via FetchModuleImportPath, since the fetch
+// module isn't necessarily generated next to every file; WebSocketDuplexTransport
+// is only imported alongside it when a service has a bidi-streaming method.
+const fileTemplateSrc = `{{if .Services}}import { Transport{{if .Services.NeedsDuplexTransport}}, WebSocketDuplexTransport{{end}} } from "{{.FetchModuleImportPath}}"
+{{end}}
+{{- range .Messages}}
+export interface {{.Name}} {
+{{- range .Fields}}
+  {{.JSONName}}: {{tsFieldType .}}
+{{- end}}
+}
+{{end}}
+{{- range .Services}}
+export class {{.Name}} {
+  constructor(private readonly transport: Transport{{if .NeedsDuplexTransport}}, private readonly duplex: WebSocketDuplexTransport{{end}}) {}
+{{range .Methods}}
+  {{.Name}}(req: {{if and .ClientStreaming .ServerStreaming}}AsyncIterable<{{.RequestType.Name}}>{{else}}{{.RequestType.Name}}{{end}}): {{if and .ClientStreaming .ServerStreaming}}AsyncIterable<{{.ResponseType.Name}}>{{else if .ServerStreaming}}AsyncIterable<{{.ResponseType.Name}}>{{else}}Promise<{{.ResponseType.Name}}>{{end}} {
+    {{if and .ClientStreaming .ServerStreaming}}return this.duplex.duplex<{{.RequestType.Name}}, {{.ResponseType.Name}}>("{{.Name}}", req){{else if .ServerStreaming}}return this.transport.requestStream<{{.ResponseType.Name}}>("{{.HTTPMethod}}", "{{.Pattern}}", req){{else}}return this.transport.request<{{.ResponseType.Name}}>("{{.HTTPMethod}}", "{{.Pattern}}", req){{end}}
+  }
+{{end}}
+}
+{{end -}}
+`
+
+// GetTemplate returns the template used to render a single proto file's
+// messages and services into TS. r is accepted for parity with the rest of
+// the generator (e.g. a future version could vary the template by registry
+// configuration) but isn't consulted yet.
+func GetTemplate(r *registry.Registry) *template.Template {
+	return template.Must(template.New("file.ts.tmpl").Funcs(FuncMap).Parse(fileTemplateSrc))
+}
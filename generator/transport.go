@@ -0,0 +1,304 @@
+package generator
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TransportOption selects which Transport implementation the fetch module
+	// template is rendered against (fetch|axios|xhr|node-fetch|custom)
+	TransportOption = "transport"
+	// CustomTransportImportOption gives the import path for a user supplied
+	// Transport implementation, used when TransportOption is "custom"
+	CustomTransportImportOption = "custom_transport_import"
+
+	transportFetch     = "fetch"
+	transportAxios     = "axios"
+	transportXHR       = "xhr"
+	transportNodeFetch = "node-fetch"
+	transportCustom    = "custom"
+
+	// StreamingOption picks how server-streaming responses are decoded
+	// (sse|ndjson|auto). Defaults to ndjson, grpc-gateway's current behaviour.
+	StreamingOption = "streaming"
+
+	streamingSSE    = "sse"
+	streamingNDJSON = "ndjson"
+	streamingAuto   = "auto"
+)
+
+// fetchModuleTemplates holds one fetch module body per supported transport.
+// Every variant renders the same `Transport` interface and request helpers,
+// only the low level request function backing it differs.
+var fetchModuleTemplates = map[string]string{
+	transportFetch:     fetchModuleTemplate,
+	transportAxios:     axiosModuleTemplate,
+	transportXHR:       xhrModuleTemplate,
+	transportNodeFetch: nodeFetchModuleTemplate,
+}
+
+// GetFetchModuleTemplate returns the fetch module template for the selected
+// transport. For transport=="custom", customTransportImport is spliced in as
+// the import path backing the generated `Transport` implementation. streaming
+// picks the default server-streaming framing (sse|ndjson|auto) used by
+// requestStream on transports that can back it with a ReadableStream.
+func GetFetchModuleTemplate(transport, customTransportImport, streaming string) (*template.Template, error) {
+	if transport == "" {
+		transport = transportFetch
+	}
+	if streaming == "" {
+		streaming = streamingNDJSON
+	}
+
+	if transport == transportCustom {
+		return template.New("fetch.module.ts.tmpl").Funcs(FuncMap).Parse(
+			customTransportModuleTemplate(customTransportImport) + duplexTransportTemplate,
+		)
+	}
+
+	body, ok := fetchModuleTemplates[transport]
+	if !ok {
+		return nil, errors.Errorf("unknown transport %q, expected one of fetch, axios, xhr, node-fetch, custom", transport)
+	}
+
+	if transport == transportFetch || transport == transportNodeFetch {
+		body = strings.ReplaceAll(body, "{{DEFAULT_FRAMING}}", streaming)
+	}
+	body += duplexTransportTemplate
+
+	return template.New("fetch.module.ts.tmpl").Funcs(FuncMap).Parse(body)
+}
+
+// duplexTransportTemplate backs bidi-streaming RPCs with a WebSocket,
+// multiplexing every outbound request over a single connection using a
+// small JSON framing protocol: each frame is one WebSocket message of the
+// form "<requestId>\t<json>", and responses are matched back to their
+// AsyncIterable by requestId. Cancellation is AbortSignal-driven and iteration is
+// backpressure-aware: frames queue until the consumer pulls the next value.
+const duplexTransportTemplate = `
+export class WebSocketDuplexTransport {
+  private socket?: WebSocket
+  private nextRequestId = 0
+  private pending = new Map<number, { queue: unknown[]; resolveNext?: (v: IteratorResult<unknown>) => void; closed: boolean }>()
+
+  constructor(private readonly url: string) {}
+
+  private ensureSocket(): WebSocket {
+    if (!this.socket || this.socket.readyState === WebSocket.CLOSED) {
+      this.socket = new WebSocket(this.url)
+      this.socket.onmessage = (ev: MessageEvent) => this.onFrame(String(ev.data))
+    }
+    return this.socket
+  }
+
+  private onFrame(raw: string) {
+    const tab = raw.indexOf("\t")
+    const requestId = Number(raw.slice(0, tab))
+    const entry = this.pending.get(requestId)
+    if (!entry) return
+    const payload = JSON.parse(raw.slice(tab + 1))
+    if (entry.resolveNext) {
+      entry.resolveNext({ value: payload, done: false })
+      entry.resolveNext = undefined
+    } else {
+      entry.queue.push(payload)
+    }
+  }
+
+  duplex<TReq, TRes>(method: string, requests: AsyncIterable<TReq>, signal?: AbortSignal): AsyncIterable<TRes> {
+    const socket = this.ensureSocket()
+    const requestId = this.nextRequestId++
+    const entry = { queue: [] as unknown[], closed: false }
+    this.pending.set(requestId, entry)
+
+    const send = async () => {
+      for await (const req of requests) {
+        socket.send(requestId + "\t" + JSON.stringify({ method, req }))
+      }
+    }
+    send()
+
+    signal?.addEventListener("abort", () => {
+      entry.closed = true
+      this.pending.delete(requestId)
+    })
+
+    return {
+      [Symbol.asyncIterator](): AsyncIterator<TRes> {
+        return {
+          next(): Promise<IteratorResult<TRes>> {
+            if (entry.closed) return Promise.resolve({ value: undefined, done: true })
+            if (entry.queue.length > 0) {
+              return Promise.resolve({ value: entry.queue.shift() as TRes, done: false })
+            }
+            return new Promise((resolve) => {
+              entry.resolveNext = resolve as (v: IteratorResult<unknown>) => void
+            })
+          },
+        }
+      },
+    }
+  }
+
+  close() {
+    this.socket?.close()
+    this.pending.clear()
+  }
+}
+`
+
+func customTransportModuleTemplate(customTransportImport string) string {
+	return `import { Transport } from "` + customTransportImport + `"
+
+export { Transport }
+`
+}
+
+// streamDecoderHelper parses a ReadableStream<Uint8Array> into an
+// AsyncIterable<T>, decoding either newline-delimited JSON (grpc-gateway's
+// current server-streaming behaviour) or a `text/event-stream`, picking the
+// framing from the response's content-type when defaultFraming is "auto".
+const streamDecoderHelper = `async function* decodeStream<T>(body: ReadableStream<Uint8Array>, contentType: string | null, defaultFraming: string): AsyncIterable<T> {
+  const framing = defaultFraming === "auto" ? (contentType?.includes("event-stream") ? "sse" : "ndjson") : defaultFraming
+  const reader = body.pipeThrough(new TextDecoderStream()).getReader()
+  let buffer = ""
+  while (true) {
+    const { value, done } = await reader.read()
+    if (done) break
+    buffer += value
+    let idx: number
+    while ((idx = buffer.indexOf("\n")) >= 0) {
+      const line = buffer.slice(0, idx)
+      buffer = buffer.slice(idx + 1)
+      const payload = framing === "sse" ? line.replace(/^data:\s*/, "") : line
+      if (payload.trim().length > 0) {
+        yield JSON.parse(payload) as T
+      }
+    }
+  }
+}
+`
+
+// fetchModuleTemplate is the default browser/Node 18+ fetch() backed Transport.
+const fetchModuleTemplate = streamDecoderHelper + `
+export interface Transport {
+  request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T>
+  requestStream<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): AsyncIterable<T>
+}
+
+export class FetchTransport implements Transport {
+  async request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T> {
+    const res = await fetch(path, {
+      method,
+      body: body !== undefined ? JSON.stringify(body) : undefined,
+      headers: { "Content-Type": "application/json", ...headers },
+      signal,
+    })
+    return res.json() as Promise<T>
+  }
+
+  requestStream<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): AsyncIterable<T> {
+    const responsePromise = fetch(path, {
+      method,
+      body: body !== undefined ? JSON.stringify(body) : undefined,
+      headers: { "Content-Type": "application/json", ...headers },
+      signal,
+    })
+    return (async function* () {
+      const res = await responsePromise
+      if (!res.body) return
+      yield* decodeStream<T>(res.body, res.headers.get("content-type"), "{{DEFAULT_FRAMING}}")
+    })()
+  }
+}
+`
+
+// axiosModuleTemplate backs the Transport interface with an axios instance.
+// axios has no portable way to hand back a raw byte stream, so requestStream
+// throws; pick transport=fetch or transport=node-fetch for streaming RPCs.
+const axiosModuleTemplate = `import axios, { AxiosRequestConfig } from "axios"
+
+export interface Transport {
+  request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T>
+  requestStream<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): AsyncIterable<T>
+}
+
+export class AxiosTransport implements Transport {
+  async request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T> {
+    const config: AxiosRequestConfig = { method, url: path, data: body, headers, signal }
+    const res = await axios.request<T>(config)
+    return res.data
+  }
+
+  requestStream<T>(): AsyncIterable<T> {
+    throw new Error("AxiosTransport does not support server-streaming RPCs; use transport=fetch or transport=node-fetch")
+  }
+}
+`
+
+// xhrModuleTemplate backs the Transport interface with XMLHttpRequest, for
+// environments without fetch(). XHR's progress events can't be turned into a
+// lazily-consumed AsyncIterable, so requestStream throws.
+const xhrModuleTemplate = `export interface Transport {
+  request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T>
+  requestStream<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): AsyncIterable<T>
+}
+
+export class XHRTransport implements Transport {
+  request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T> {
+    return new Promise((resolve, reject) => {
+      const xhr = new XMLHttpRequest()
+      xhr.open(method, path)
+      Object.entries(headers || {}).forEach(([key, value]) => xhr.setRequestHeader(key, value))
+      xhr.onload = () => resolve(JSON.parse(xhr.responseText) as T)
+      xhr.onerror = () => reject(xhr.statusText)
+      signal?.addEventListener("abort", () => xhr.abort())
+      xhr.send(body !== undefined ? JSON.stringify(body) : undefined)
+    })
+  }
+
+  requestStream<T>(): AsyncIterable<T> {
+    throw new Error("XHRTransport does not support server-streaming RPCs; use transport=fetch or transport=node-fetch")
+  }
+}
+`
+
+// nodeFetchModuleTemplate backs the Transport interface with node-fetch, for
+// Node SSR / server components environments predating global fetch().
+const nodeFetchModuleTemplate = streamDecoderHelper + `
+import fetch from "node-fetch"
+
+export interface Transport {
+  request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T>
+  requestStream<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): AsyncIterable<T>
+}
+
+export class NodeFetchTransport implements Transport {
+  async request<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): Promise<T> {
+    const res = await fetch(path, {
+      method,
+      body: body !== undefined ? JSON.stringify(body) : undefined,
+      headers: { "Content-Type": "application/json", ...headers },
+      signal,
+    })
+    return res.json() as Promise<T>
+  }
+
+  requestStream<T>(method: string, path: string, body?: unknown, headers?: Record<string, string>, signal?: AbortSignal): AsyncIterable<T> {
+    const responsePromise = fetch(path, {
+      method,
+      body: body !== undefined ? JSON.stringify(body) : undefined,
+      headers: { "Content-Type": "application/json", ...headers },
+      signal,
+    })
+    return (async function* () {
+      const res = await responsePromise
+      if (!res.body) return
+      yield* decodeStream<T>(res.body as unknown as ReadableStream<Uint8Array>, res.headers.get("content-type"), "{{DEFAULT_FRAMING}}")
+    })()
+  }
+}
+`
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFetchModuleTemplate(t *testing.T) {
+	for _, transport := range []string{"", "fetch", "axios", "xhr", "node-fetch"} {
+		t.Run(transport, func(t *testing.T) {
+			tmpl, err := GetFetchModuleTemplate(transport, "", "")
+			require.NoError(t, err)
+			assert.NotNil(t, tmpl)
+		})
+	}
+}
+
+func TestGetFetchModuleTemplate_Custom(t *testing.T) {
+	tmpl, err := GetFetchModuleTemplate("custom", "./my-transport", "")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, nil))
+	assert.Contains(t, buf.String(), "./my-transport")
+}
+
+func TestGetFetchModuleTemplate_UnknownTransport(t *testing.T) {
+	_, err := GetFetchModuleTemplate("carrier-pigeon", "", "")
+	assert.Error(t, err)
+}
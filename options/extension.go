@@ -0,0 +1,99 @@
+package options
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// scenarioExecutorFieldNumber is the `scenario_executor` MethodOptions
+// extension field number declared in scenario.proto.
+const scenarioExecutorFieldNumber = 50101
+
+// FromMethodOptions reads the scenario_executor extension off opts without
+// requiring generated protoc-gen-go bindings for scenario.proto: protoc
+// leaves extensions it doesn't recognize as unknown fields on the
+// surrounding message, so this walks the raw wire bytes directly. Returns
+// nil if the method isn't annotated.
+func FromMethodOptions(opts *descriptorpb.MethodOptions) *ScenarioExecutor {
+	if opts == nil {
+		return nil
+	}
+
+	raw, ok := findField(opts.ProtoReflect().GetUnknown(), scenarioExecutorFieldNumber)
+	if !ok {
+		return nil
+	}
+
+	exec := &ScenarioExecutor{}
+	if msg, ok := findField(raw, 1); ok {
+		exec.ConstantArrivalRate = &ConstantArrivalRate{
+			Duration:        stringField(msg, 1),
+			Rate:            int32Field(msg, 2),
+			PreAllocatedVUs: int32Field(msg, 3),
+		}
+		return exec
+	}
+	if msg, ok := findField(raw, 2); ok {
+		exec.RampingVUs = &RampingVUs{
+			Duration:        stringField(msg, 1),
+			PreAllocatedVUs: int32Field(msg, 2),
+		}
+		return exec
+	}
+	return exec
+}
+
+// findField scans a message's raw wire bytes for the last occurrence of
+// fieldNumber and returns its content alone. That matches protobuf's "last
+// one wins" rule for scalar fields; repeated occurrences of an embedded
+// message are not merged, only the last is kept. Length-delimited fields (strings, embedded messages) are
+// returned with their length prefix already stripped; varint fields are
+// returned as their still-encoded bytes for ConsumeVarint to decode.
+func findField(b []byte, fieldNumber protowire.Number) ([]byte, bool) {
+	var value []byte
+	found := false
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, false
+		}
+		b = b[n:]
+
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return nil, false
+		}
+		if num == fieldNumber {
+			switch typ {
+			case protowire.BytesType:
+				v, _ := protowire.ConsumeBytes(b[:m])
+				value = v
+			default:
+				value = b[:m]
+			}
+			found = true
+		}
+		b = b[m:]
+	}
+	return value, found
+}
+
+func stringField(b []byte, fieldNumber protowire.Number) string {
+	v, ok := findField(b, fieldNumber)
+	if !ok {
+		return ""
+	}
+	return string(v)
+}
+
+func int32Field(b []byte, fieldNumber protowire.Number) int32 {
+	raw, ok := findField(b, fieldNumber)
+	if !ok {
+		return 0
+	}
+	val, n := protowire.ConsumeVarint(raw)
+	if n < 0 {
+		return 0
+	}
+	return int32(val)
+}
@@ -0,0 +1,27 @@
+// Package options mirrors the types declared in scenario.proto. registry.Analyse
+// reads the `scenario_executor` google.protobuf.MethodOptions extension off each
+// RPC and attaches the resulting ScenarioExecutor to data.Method, so the k6
+// scenario generator never has to touch descriptor protos directly.
+package options
+
+// ScenarioExecutor is the k6 executor configuration attached to an RPC via
+// the `scenario_executor` method option. At most one of ConstantArrivalRate
+// or RampingVUs is set, mirroring the proto `oneof executor`; neither is set
+// when the annotation leaves the oneof empty.
+type ScenarioExecutor struct {
+	ConstantArrivalRate *ConstantArrivalRate
+	RampingVUs          *RampingVUs
+}
+
+// ConstantArrivalRate mirrors k6's constant-arrival-rate executor.
+type ConstantArrivalRate struct {
+	Duration        string
+	Rate            int32
+	PreAllocatedVUs int32
+}
+
+// RampingVUs mirrors k6's ramping-vus executor.
+type RampingVUs struct {
+	Duration        string
+	PreAllocatedVUs int32
+}
@@ -0,0 +1,306 @@
+// Package registry turns a protoc CodeGeneratorRequest into the data.File
+// structures the generator package renders into TypeScript.
+package registry
+
+import (
+	"path"
+	"strings"
+
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/data"
+	"github.com/juanjiTech/protoc-gen-grpc-gateway-ts/options"
+)
+
+const (
+	// FetchModuleDirectoryOption sets the directory the shared fetch module is written to
+	FetchModuleDirectoryOption = "fetch_module_directory"
+	// FetchModuleFilenameOption sets the filename of the shared fetch module
+	FetchModuleFilenameOption = "fetch_module_filename"
+	// EditionsDefaultOption sets the default Protobuf Editions feature set
+	// (e.g. "2023") applied to files that don't set `features.field_presence`
+	// explicitly, mirroring how upstream Go generators resolve edition defaults.
+	EditionsDefaultOption = "editions_default"
+
+	defaultFetchModuleDirectory = "."
+	defaultFetchModuleFilename  = "fetch.pb.ts"
+)
+
+// editionDefaultPresence maps an edition name to the field presence it
+// defaults to absent an explicit `features.field_presence` override, per
+// https://protobuf.dev/editions/overview/#field_presence.
+var editionDefaultPresence = map[string]data.FieldPresence{
+	"2023": data.FieldPresenceExplicit,
+}
+
+// Registry resolves proto types and tracks which files protoc asked us to
+// generate, across the lifetime of a single Generate call.
+type Registry struct {
+	FetchModuleDirectory string
+	FetchModuleFilename  string
+	// EditionsDefault is the fallback presence applied to an Editions file
+	// with no explicit `features.field_presence`, keyed by EditionsDefaultOption
+	EditionsDefault string
+
+	fileToGenerate map[string]bool
+}
+
+// NewRegistry returns a Registry configured from the plugin's params map.
+func NewRegistry(params map[string]string) (*Registry, error) {
+	dir := params[FetchModuleDirectoryOption]
+	if dir == "" {
+		dir = defaultFetchModuleDirectory
+	}
+	filename := params[FetchModuleFilenameOption]
+	if filename == "" {
+		filename = defaultFetchModuleFilename
+	}
+
+	return &Registry{
+		FetchModuleDirectory: dir,
+		FetchModuleFilename:  filename,
+		EditionsDefault:      params[EditionsDefaultOption],
+	}, nil
+}
+
+// IsFileToGenerate reports whether name was one of the files protoc asked us
+// to generate, as opposed to a file only present for import resolution.
+func (r *Registry) IsFileToGenerate(name string) bool {
+	return r.fileToGenerate[name]
+}
+
+// Analyse walks every proto file in the request and builds its data.File.
+func (r *Registry) Analyse(req *plugin.CodeGeneratorRequest) ([]*data.File, error) {
+	r.fileToGenerate = make(map[string]bool, len(req.GetFileToGenerate()))
+	for _, name := range req.GetFileToGenerate() {
+		r.fileToGenerate[name] = true
+	}
+
+	messagesByName := map[string]*data.Message{}
+	for _, fd := range req.GetProtoFile() {
+		for _, msg := range fd.GetMessageType() {
+			messagesByName[fullName(fd, msg.GetName())] = r.analyseMessage(fd, msg)
+		}
+	}
+
+	files := make([]*data.File, 0, len(req.GetProtoFile()))
+	for _, fd := range req.GetProtoFile() {
+		files = append(files, r.analyseFile(fd, messagesByName))
+	}
+	return files, nil
+}
+
+func fullName(fd *descriptorpb.FileDescriptorProto, name string) string {
+	if pkg := fd.GetPackage(); pkg != "" {
+		return "." + pkg + "." + name
+	}
+	return "." + name
+}
+
+func tsFileName(protoName string) string {
+	return strings.TrimSuffix(protoName, ".proto") + ".pb.ts"
+}
+
+// fetchModuleImportPath computes the TS import specifier a file generated at
+// protoFile's location uses to reach the shared fetch module at
+// fetchModuleDirectory/fetchModuleFilename, relative to protoFile's own
+// directory rather than assuming the fetch module sits next to every
+// generated file.
+func fetchModuleImportPath(protoFile, fetchModuleDirectory, fetchModuleFilename string) string {
+	fromDir := path.Dir(protoFile)
+	toDir := path.Clean(fetchModuleDirectory)
+	toBase := strings.TrimSuffix(fetchModuleFilename, ".ts")
+
+	var fromParts, toDirParts []string
+	if fromDir != "." {
+		fromParts = strings.Split(fromDir, "/")
+	}
+	if toDir != "." {
+		toDirParts = strings.Split(toDir, "/")
+	}
+
+	i := 0
+	for i < len(fromParts) && i < len(toDirParts) && fromParts[i] == toDirParts[i] {
+		i++
+	}
+
+	var segments []string
+	for range fromParts[i:] {
+		segments = append(segments, "..")
+	}
+	segments = append(segments, toDirParts[i:]...)
+	segments = append(segments, toBase)
+
+	importPath := strings.Join(segments, "/")
+	if !strings.HasPrefix(importPath, ".") {
+		importPath = "./" + importPath
+	}
+	return importPath
+}
+
+func (r *Registry) analyseFile(fd *descriptorpb.FileDescriptorProto, messagesByName map[string]*data.Message) *data.File {
+	file := &data.File{
+		Name:                  fd.GetName(),
+		TSFileName:            tsFileName(fd.GetName()),
+		FetchModuleImportPath: fetchModuleImportPath(fd.GetName(), r.FetchModuleDirectory, r.FetchModuleFilename),
+	}
+
+	for _, msg := range fd.GetMessageType() {
+		file.Messages = append(file.Messages, r.analyseMessage(fd, msg))
+	}
+
+	for _, svc := range fd.GetService() {
+		file.Services = append(file.Services, analyseService(svc, messagesByName))
+	}
+
+	return file
+}
+
+func (r *Registry) analyseMessage(fd *descriptorpb.FileDescriptorProto, msg *descriptorpb.DescriptorProto) *data.Message {
+	message := &data.Message{Name: msg.GetName()}
+	for _, field := range msg.GetField() {
+		message.Fields = append(message.Fields, &data.Field{
+			Name:     field.GetName(),
+			JSONName: field.GetJsonName(),
+			Type:     tsScalarType(field),
+			Presence: r.fieldPresence(fd, msg, field),
+		})
+	}
+	return message
+}
+
+func analyseService(svc *descriptorpb.ServiceDescriptorProto, messagesByName map[string]*data.Message) *data.Service {
+	service := &data.Service{Name: svc.GetName()}
+	for _, method := range svc.GetMethod() {
+		httpMethod, pattern, body := httpBinding(svc.GetName(), method)
+		service.Methods = append(service.Methods, &data.Method{
+			Name:             method.GetName(),
+			HTTPMethod:       httpMethod,
+			Pattern:          pattern,
+			Body:             body,
+			RequestType:      messagesByName[method.GetInputType()],
+			ResponseType:     messagesByName[method.GetOutputType()],
+			ServerStreaming:  method.GetServerStreaming(),
+			ClientStreaming:  method.GetClientStreaming(),
+			ScenarioExecutor: options.FromMethodOptions(method.GetOptions()),
+		})
+	}
+	return service
+}
+
+// httpBinding resolves a method's google.api.http annotation into an HTTP
+// verb, URL pattern and body selector. Methods without the annotation fall
+// back to a POST to "/<service>/<method>", grpc-gateway's own default.
+func httpBinding(serviceName string, method *descriptorpb.MethodDescriptorProto) (httpMethod, pattern, body string) {
+	rule, ok := proto.GetExtension(method.GetOptions(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return "POST", "/" + serviceName + "/" + method.GetName(), "*"
+	}
+
+	switch {
+	case rule.GetGet() != "":
+		return "GET", rule.GetGet(), rule.GetBody()
+	case rule.GetPut() != "":
+		return "PUT", rule.GetPut(), rule.GetBody()
+	case rule.GetPost() != "":
+		return "POST", rule.GetPost(), rule.GetBody()
+	case rule.GetDelete() != "":
+		return "DELETE", rule.GetDelete(), rule.GetBody()
+	case rule.GetPatch() != "":
+		return "PATCH", rule.GetPatch(), rule.GetBody()
+	default:
+		return "POST", "/" + serviceName + "/" + method.GetName(), rule.GetBody()
+	}
+}
+
+// fieldPresence derives a field's presence semantics from proto2/proto3/
+// Editions descriptor metadata, checking in order: an explicit Editions
+// feature override on the field, then proto2 `required`, then a proto3
+// `optional` (marked via proto3_optional) or a proto2 `optional` label, then,
+// for an Editions file, an override on the message and then on the file. An
+// Editions file with no override anywhere takes the default of the edition
+// named by r.EditionsDefault; when that names no known edition, and for plain
+// proto3 fields, presence is implicit.
+func (r *Registry) fieldPresence(fd *descriptorpb.FileDescriptorProto, msg *descriptorpb.DescriptorProto, field *descriptorpb.FieldDescriptorProto) data.FieldPresence {
+	if p, ok := featurePresence(field.GetOptions().GetFeatures()); ok {
+		return p
+	}
+
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED {
+		return data.FieldPresenceLegacyRequired
+	}
+	if field.GetProto3Optional() {
+		return data.FieldPresenceExplicit
+	}
+	if (fd.GetSyntax() == "" || fd.GetSyntax() == "proto2") && field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL {
+		return data.FieldPresenceExplicit
+	}
+
+	if fd.GetSyntax() == "editions" {
+		if p, ok := featurePresence(msg.GetOptions().GetFeatures()); ok {
+			return p
+		}
+		if p, ok := featurePresence(fd.GetOptions().GetFeatures()); ok {
+			return p
+		}
+		if p, ok := editionDefaultPresence[r.EditionsDefault]; ok {
+			return p
+		}
+	}
+
+	return data.FieldPresenceImplicit
+}
+
+// featurePresence reads an explicit `field_presence` feature override, if set.
+func featurePresence(features *descriptorpb.FeatureSet) (data.FieldPresence, bool) {
+	switch features.GetFieldPresence() {
+	case descriptorpb.FeatureSet_EXPLICIT:
+		return data.FieldPresenceExplicit, true
+	case descriptorpb.FeatureSet_IMPLICIT:
+		return data.FieldPresenceImplicit, true
+	case descriptorpb.FeatureSet_LEGACY_REQUIRED:
+		return data.FieldPresenceLegacyRequired, true
+	default:
+		return 0, false
+	}
+}
+
+// tsScalarType maps a proto field kind to the TS type it round-trips through
+// as JSON, ignoring presence (tsFieldType layers presence on top of this).
+func tsScalarType(field *descriptorpb.FieldDescriptorProto) string {
+	var base string
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+		descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+		descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		base = "number"
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		base = "string"
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		base = "boolean"
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		base = "Uint8Array"
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		base = strings.TrimPrefix(field.GetTypeName(), ".")
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		base = strings.TrimPrefix(field.GetTypeName(), ".")
+	default:
+		base = "string"
+	}
+
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return base + "[]"
+	}
+	return base
+}